@@ -0,0 +1,13 @@
+//go:build !opensearch
+
+package main
+
+import (
+	"fmt"
+
+	"sewik/pkg/sink"
+)
+
+func newOpenSearchSink(url, index string, numWorkers, flushBytes int) (sink.Sink, error) {
+	return nil, fmt.Errorf("-sink=opensearch requires building with -tags opensearch")
+}
@@ -0,0 +1,14 @@
+//go:build opensearch
+
+package main
+
+import "sewik/pkg/sink"
+
+func newOpenSearchSink(url, index string, numWorkers, flushBytes int) (sink.Sink, error) {
+	return sink.NewOpenSearchSink(sink.OpenSearchConfig{
+		URL:        url,
+		Index:      index,
+		NumWorkers: numWorkers,
+		FlushBytes: flushBytes,
+	})
+}
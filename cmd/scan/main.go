@@ -13,6 +13,7 @@ import (
 	"sewik/pkg/dom/stats"
 	"sewik/pkg/sewik"
 	"sewik/pkg/sys"
+	"sewik/xml/print/schema"
 )
 
 var cpuFile = flag.String("profile.cpu", "", "write cpu profile to `file`")
@@ -21,7 +22,7 @@ var workNum = flag.Int("w", 8, "worker pool size")
 var pipeSize = flag.Int("p", 10000, "pipe size per one worker")
 var procNum = flag.Int("n", runtime.GOMAXPROCS(0), "set GOMAXPROCS = n")
 var procDiv = flag.Int("d", 2, "set GOMAXPROCS /= d")
-var cmd = flag.String("c", "x", "xml|json")
+var cmd = flag.String("c", "x", "x|j|v|schema|avro")
 
 func main() {
 	start := time.Now()
@@ -83,6 +84,22 @@ func printXMLStats(filenames <-chan string, workerNum int, pipeSize int) {
 	stats.PrintXML(elements)
 }
 
+func printSchema(filenames <-chan string, workerNum int, pipeSize int) {
+	elements := stats.NewElementsWithLock()
+	for e := range sewik.ElementsOf("ZDARZENIE", filenames, workerNum, workerNum*(pipeSize+1)) {
+		elements.Add(e)
+	}
+	schema.PrintJSON(elements)
+}
+
+func printAvro(filenames <-chan string, workerNum int, pipeSize int) {
+	elements := stats.NewElementsWithLock()
+	for e := range sewik.ElementsOf("ZDARZENIE", filenames, workerNum, workerNum*(pipeSize+1)) {
+		elements.Add(e)
+	}
+	schema.PrintAvro("ZDARZENIE", elements)
+}
+
 func commands(s string, workerCount int, pipeSize int) {
 	filenames := sys.Filenames(flag.Args(), 500)
 	switch s {
@@ -92,6 +109,10 @@ func commands(s string, workerCount int, pipeSize int) {
 		printJSON(filenames, workerCount, pipeSize)
 	case "v":
 		printVar(filenames, workerCount, pipeSize)
+	case "schema":
+		printSchema(filenames, workerCount, pipeSize)
+	case "avro":
+		printAvro(filenames, workerCount, pipeSize)
 	}
 }
 
@@ -6,31 +6,51 @@ import (
 	"sewik/pkg/dom"
 )
 
+// Attributes tracks how often each attribute name is seen, and the
+// distribution of the values it takes on.
 type Attributes interface {
 	Add(n *dom.Attribute)
+	// AddValue records one occurrence of name=value. It is the path Add
+	// funnels through, exposed directly for callers that already have
+	// the name and value apart from a *dom.Attribute.
+	AddValue(name, value string)
 	Get() attributeMap
 	Len() int
+	// TopValues returns the approximate k most frequent values seen for
+	// name, most frequent first. Values that parsed as numbers are not
+	// included; see Quantiles for those.
+	TopValues(name string, k int) []ValueCount
+	// Quantiles returns the approximate p50/p90/p99 of the numeric-looking
+	// values seen for name, keyed by "p50", "p90", "p99". It is nil if no
+	// value for name ever parsed as a number.
+	Quantiles(name string) map[string]float64
 }
 type attribute = int
 
 func newAttributesWithLock() Attributes {
 	return &attributesWithLock{
-		in: make(attributeMap),
+		in:     make(attributeMap),
+		values: make(map[string]*valueSummary),
 	}
 }
 
 type attributeMap map[string]attribute
 
 type attributesWithLock struct {
-	mx sync.Mutex
-	in attributeMap
+	mx     sync.Mutex
+	in     attributeMap
+	values map[string]*valueSummary
 }
 
 func (a *attributesWithLock) Add(n *dom.Attribute) {
+	a.AddValue(n.Name, n.Value)
+}
+
+func (a *attributesWithLock) AddValue(name, value string) {
 	a.mx.Lock()
 	defer a.mx.Unlock()
 
-	x, exists := a.in[n.Name]
+	x, exists := a.in[name]
 
 	if exists {
 		x++
@@ -38,13 +58,42 @@ func (a *attributesWithLock) Add(n *dom.Attribute) {
 		x = 1
 	}
 
-	a.in[n.Name] = x
+	a.in[name] = x
+
+	vs, exists := a.values[name]
+	if !exists {
+		vs = newValueSummary()
+		a.values[name] = vs
+	}
+	vs.add(value)
 }
 
-func (a attributesWithLock) Get() attributeMap {
+func (a *attributesWithLock) Get() attributeMap {
 	return a.in
 }
 
-func (a attributesWithLock) Len() int {
+func (a *attributesWithLock) Len() int {
 	return len(a.in)
-}
\ No newline at end of file
+}
+
+func (a *attributesWithLock) TopValues(name string, k int) []ValueCount {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	vs, exists := a.values[name]
+	if !exists {
+		return nil
+	}
+	return vs.topValues(k)
+}
+
+func (a *attributesWithLock) Quantiles(name string) map[string]float64 {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	vs, exists := a.values[name]
+	if !exists {
+		return nil
+	}
+	return vs.quantiles()
+}
@@ -0,0 +1,134 @@
+package stats
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"sewik/pkg/dom"
+)
+
+// Elements tracks, for every element name seen while walking parsed
+// ZDARZENIE trees, how many times it occurred, the distribution of its
+// attribute values, and the same tracking recursively for its children.
+type Elements interface {
+	Add(e *dom.Element)
+	Get() elementMap
+	Len() int
+}
+
+type element struct {
+	Cn int
+	At Attributes
+	El Elements
+}
+
+type elementMap map[string]*element
+
+// NewElementsWithLock returns a concurrency-safe Elements accumulator
+// suitable for feeding from a worker pool, the same way
+// newAttributesWithLock is for the attributes of a single element.
+func NewElementsWithLock() Elements {
+	return &elementsWithLock{in: make(elementMap)}
+}
+
+type elementsWithLock struct {
+	mx sync.Mutex
+	in elementMap
+}
+
+func (e *elementsWithLock) Add(el *dom.Element) {
+	e.mx.Lock()
+	x, exists := e.in[el.Name]
+	if !exists {
+		x = &element{At: newAttributesWithLock(), El: NewElementsWithLock()}
+		e.in[el.Name] = x
+	}
+	x.Cn++
+	e.mx.Unlock()
+
+	for _, a := range el.Attributes {
+		x.At.Add(a)
+	}
+	for _, c := range el.Children {
+		x.El.Add(c)
+	}
+}
+
+func (e *elementsWithLock) Get() elementMap {
+	return e.in
+}
+
+func (e *elementsWithLock) Len() int {
+	return len(e.in)
+}
+
+// PrintXML writes elements as the pseudo-XML dump the "x" command has
+// always produced, annotated with _count/_optional the way it always
+// has been, plus _topValues and _quantiles for each attribute now that
+// Attributes tracks value distributions and not just counts.
+func PrintXML(elements Elements) {
+	printElements(elements, 0, 0)
+	fmt.Println()
+}
+
+func printElements(elements Elements, l, c int) {
+	for k, el := range elements.Get() {
+		fmt.Printf("\n%s<%s", strings.Repeat("  ", l), k)
+
+		fmt.Printf(` _count="%d"`, el.Cn)
+
+		if el.Cn < c {
+			fmt.Print(` _optional="true"`)
+		}
+
+		printAttributes(el.At)
+
+		if el.El.Len() > 0 {
+			fmt.Print(">")
+
+			printElements(el.El, l+1, el.Cn)
+
+			fmt.Printf("\n%s</%s>", strings.Repeat("  ", l), k)
+		} else {
+			fmt.Print(" />")
+		}
+	}
+}
+
+func printAttributes(attrs Attributes) {
+	const topValuesK = 20
+
+	for name, count := range attrs.Get() {
+		fmt.Printf(` %s="%d"`, name, count)
+
+		if top := attrs.TopValues(name, topValuesK); len(top) > 0 {
+			fmt.Printf(` %s_topValues="%s"`, name, formatTopValues(top))
+		}
+
+		if q := attrs.Quantiles(name); q != nil {
+			fmt.Printf(` %s_quantiles="%s"`, name, formatQuantiles(q))
+		}
+	}
+}
+
+func formatTopValues(top []ValueCount) string {
+	parts := make([]string, len(top))
+	for i, vc := range top {
+		// vc.Value is arbitrary attribute data and may itself contain
+		// ":" or "," (a time, free text, ...), so it's quoted to keep
+		// the value/count pairs unambiguous.
+		parts[i] = fmt.Sprintf("%s:%d", strconv.Quote(vc.Value), vc.Count)
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatQuantiles(q map[string]float64) string {
+	parts := make([]string, 0, len(quantileTargets))
+	for _, p := range quantileTargets {
+		label := quantileLabel(p)
+		parts = append(parts, fmt.Sprintf("%s:%.2f", label, q[label]))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,15 @@
+package stats
+
+import "testing"
+
+func TestFormatTopValuesQuotesValuesContainingDelimiters(t *testing.T) {
+	got := formatTopValues([]ValueCount{
+		{Value: "12:30:00", Count: 5},
+		{Value: "a,b", Count: 2},
+	})
+
+	want := `"12:30:00":5,"a,b":2`
+	if got != want {
+		t.Fatalf("formatTopValues() = %q, want %q", got, want)
+	}
+}
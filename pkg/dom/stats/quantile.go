@@ -0,0 +1,81 @@
+package stats
+
+import "sort"
+
+// gkTuple is one entry of a Greenwald-Khanna summary: value, together
+// with g (the minimum possible rank gap to the previous tuple) and delta
+// (the maximum possible rank gap), which bound the rank error at value.
+type gkTuple struct {
+	value float64
+	g     int
+	delta int
+}
+
+// quantileSketch is a Greenwald-Khanna summary that answers approximate
+// quantile queries for a stream of numbers in bounded memory: the number
+// of tuples it retains stays O(1/epsilon * log(epsilon*n)) regardless of
+// how many values are added.
+type quantileSketch struct {
+	epsilon float64
+	tuples  []gkTuple
+	n       int
+}
+
+func newQuantileSketch(epsilon float64) *quantileSketch {
+	return &quantileSketch{epsilon: epsilon}
+}
+
+func (q *quantileSketch) add(v float64) {
+	i := sort.Search(len(q.tuples), func(i int) bool { return q.tuples[i].value >= v })
+
+	delta := 0
+	if i > 0 && i < len(q.tuples) {
+		delta = int(2 * q.epsilon * float64(q.n))
+	}
+
+	q.tuples = append(q.tuples, gkTuple{})
+	copy(q.tuples[i+1:], q.tuples[i:])
+	q.tuples[i] = gkTuple{value: v, g: 1, delta: delta}
+
+	q.n++
+
+	compressEvery := int(1 / (2 * q.epsilon))
+	if compressEvery > 0 && q.n%compressEvery == 0 {
+		q.compress()
+	}
+}
+
+func (q *quantileSketch) compress() {
+	threshold := int(2 * q.epsilon * float64(q.n))
+
+	for i := len(q.tuples) - 2; i >= 1; i-- {
+		if q.tuples[i].g+q.tuples[i+1].g+q.tuples[i+1].delta <= threshold {
+			q.tuples[i+1].g += q.tuples[i].g
+			q.tuples = append(q.tuples[:i], q.tuples[i+1:]...)
+		}
+	}
+}
+
+// query returns the value at approximate quantile phi (0..1), accurate
+// to within epsilon*n of the true rank.
+func (q *quantileSketch) query(phi float64) float64 {
+	if len(q.tuples) == 0 {
+		return 0
+	}
+
+	rank := int(phi * float64(q.n))
+	threshold := int(q.epsilon * float64(q.n))
+
+	r := 0
+	for i, t := range q.tuples {
+		r += t.g
+		if r+t.delta > rank+threshold {
+			if i == 0 {
+				return t.value
+			}
+			return q.tuples[i-1].value
+		}
+	}
+
+	return q.tuples[len(q.tuples)-1].value
+}
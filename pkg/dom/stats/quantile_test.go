@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantileSketchApproximatesKnownDistribution(t *testing.T) {
+	const n = 1000
+	const epsilon = 0.01
+
+	q := newQuantileSketch(epsilon)
+	for i := 1; i <= n; i++ {
+		q.add(float64(i))
+	}
+
+	cases := []struct {
+		phi  float64
+		want float64
+	}{
+		{0.5, 500},
+		{0.9, 900},
+		{0.99, 990},
+	}
+
+	tolerance := epsilon * n * 2
+	for _, c := range cases {
+		got := q.query(c.phi)
+		if math.Abs(got-c.want) > tolerance {
+			t.Errorf("query(%v) = %v, want within %v of %v", c.phi, got, tolerance, c.want)
+		}
+	}
+}
+
+func TestQuantileSketchSingleValue(t *testing.T) {
+	q := newQuantileSketch(0.01)
+	q.add(42)
+
+	if got := q.query(0.5); got != 42 {
+		t.Fatalf("query(0.5) = %v, want 42", got)
+	}
+}
+
+func TestQuantileSketchEmpty(t *testing.T) {
+	q := newQuantileSketch(0.01)
+
+	if got := q.query(0.5); got != 0 {
+		t.Fatalf("query(0.5) on empty sketch = %v, want 0", got)
+	}
+}
@@ -0,0 +1,85 @@
+package stats
+
+import "sort"
+
+// ValueCount is one entry of a top-K result: a value and how many times
+// the sketch counted it.
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// topK is a fixed-capacity Misra-Gries frequency sketch. It keeps at
+// most k distinct values; once full, a miss decrements every counter,
+// evicting any that reach zero, and if that doesn't free a slot it
+// replaces whichever value has the smallest remaining count. This keeps
+// the heaviest hitters in a stream regardless of how many distinct
+// values pass through.
+type topK struct {
+	k      int
+	counts map[string]int
+}
+
+func newTopK(k int) *topK {
+	return &topK{k: k, counts: make(map[string]int, k)}
+}
+
+func (t *topK) add(v string) {
+	if _, ok := t.counts[v]; ok {
+		t.counts[v]++
+		return
+	}
+
+	if len(t.counts) < t.k {
+		t.counts[v] = 1
+		return
+	}
+
+	for value, count := range t.counts {
+		if count <= 1 {
+			delete(t.counts, value)
+		} else {
+			t.counts[value] = count - 1
+		}
+	}
+
+	if len(t.counts) < t.k {
+		t.counts[v] = 1
+		return
+	}
+
+	minValue, minCount := "", 0
+	first := true
+	for value, count := range t.counts {
+		if first || count < minCount {
+			minValue, minCount = value, count
+			first = false
+		}
+	}
+
+	delete(t.counts, minValue)
+	t.counts[v] = minCount + 1
+}
+
+// top returns the n counted values with the highest counts, most
+// frequent first. The counts are exact while the stream has held no more
+// than k distinct values; once a miss has hit a full sketch, they may be
+// below or above the true counts.
+func (t *topK) top(n int) []ValueCount {
+	out := make([]ValueCount, 0, len(t.counts))
+	for value, count := range t.counts {
+		out = append(out, ValueCount{Value: value, Count: count})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Value < out[j].Value
+	})
+
+	if n < len(out) {
+		out = out[:n]
+	}
+	return out
+}
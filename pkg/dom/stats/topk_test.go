@@ -0,0 +1,66 @@
+package stats
+
+import "testing"
+
+func TestTopKWithinCapacity(t *testing.T) {
+	tk := newTopK(3)
+	for _, v := range []string{"a", "b", "a", "c", "a"} {
+		tk.add(v)
+	}
+
+	got := tk.top(3)
+	want := []ValueCount{{Value: "a", Count: 3}, {Value: "b", Count: 1}, {Value: "c", Count: 1}}
+	assertValueCounts(t, got, want)
+}
+
+func TestTopKEvictsOnMiss(t *testing.T) {
+	tk := newTopK(2)
+	for _, v := range []string{"a", "a", "b", "c"} {
+		tk.add(v)
+	}
+
+	// a:2 b:1 fills the sketch; c is a miss while full, so every counter
+	// is decremented first (a:1, b:0 evicted), which frees a slot and c
+	// is inserted directly rather than replacing the minimum.
+	got := tk.top(2)
+	want := []ValueCount{{Value: "a", Count: 1}, {Value: "c", Count: 1}}
+	assertValueCounts(t, got, want)
+}
+
+func TestTopKReplacesMinWhenStillFullAfterDecrement(t *testing.T) {
+	tk := newTopK(2)
+	for _, v := range []string{"a", "a", "a", "b", "b", "c"} {
+		tk.add(v)
+	}
+
+	// a:3 b:2 fills the sketch; c is a miss while full, decrementing
+	// every counter (a:2, b:1) without evicting anything, so c replaces
+	// the new minimum (b) at minCount+1.
+	got := tk.top(2)
+	want := []ValueCount{{Value: "a", Count: 2}, {Value: "c", Count: 2}}
+	assertValueCounts(t, got, want)
+}
+
+func TestTopKLimitsResultCount(t *testing.T) {
+	tk := newTopK(5)
+	for _, v := range []string{"a", "b", "c", "d"} {
+		tk.add(v)
+	}
+
+	if got := len(tk.top(2)); got != 2 {
+		t.Fatalf("top(2) returned %d entries, want 2", got)
+	}
+}
+
+func assertValueCounts(t *testing.T, got, want []ValueCount) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("top() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("top()[%d] = %v, want %v (full: got=%v want=%v)", i, got[i], want[i], got, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package stats
+
+import "strconv"
+
+// defaultTopK is the number of slots given to each attribute's top-K
+// sketch. It bounds the memory a single attribute's value distribution
+// can use, regardless of how many distinct values it actually has.
+const defaultTopK = 20
+
+// quantileEpsilon is the approximation error tolerated by the quantile
+// sketch, as a fraction of the stream length.
+const quantileEpsilon = 0.01
+
+var quantileTargets = []float64{0.5, 0.9, 0.99}
+
+// valueSummary is the bounded-memory value distribution kept per
+// attribute name: a frequency sketch for values that never parse as a
+// number, and a quantile sketch for the ones that do.
+type valueSummary struct {
+	strings *topK
+	numbers *quantileSketch
+}
+
+func newValueSummary() *valueSummary {
+	return &valueSummary{strings: newTopK(defaultTopK)}
+}
+
+func (v *valueSummary) add(value string) {
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		if v.numbers == nil {
+			v.numbers = newQuantileSketch(quantileEpsilon)
+		}
+		v.numbers.add(f)
+		return
+	}
+
+	v.strings.add(value)
+}
+
+func (v *valueSummary) topValues(k int) []ValueCount {
+	return v.strings.top(k)
+}
+
+func (v *valueSummary) quantiles() map[string]float64 {
+	if v.numbers == nil {
+		return nil
+	}
+
+	out := make(map[string]float64, len(quantileTargets))
+	for _, p := range quantileTargets {
+		out[quantileLabel(p)] = v.numbers.query(p)
+	}
+	return out
+}
+
+func quantileLabel(p float64) string {
+	return "p" + strconv.Itoa(int(p*100))
+}
@@ -0,0 +1,100 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esutil"
+)
+
+// ESBulkIndexerConfig configures NewESBulkIndexer.
+type ESBulkIndexerConfig struct {
+	Index      string
+	NumWorkers int
+	FlushBytes int
+	MaxRetries int
+}
+
+// ESBulkIndexer is a Sink backed by esutil.BulkIndexer against an
+// Elasticsearch cluster. It is the default backend used by the indexer.
+type ESBulkIndexer struct {
+	bi        esutil.BulkIndexer
+	succeeded uint64
+	failed    uint64
+}
+
+// NewESBulkIndexer creates the Elasticsearch client and bulk indexer
+// described by cfg. The index is expected to already exist; callers that
+// want a fresh index should create it before constructing the sink.
+func NewESBulkIndexer(cfg ESBulkIndexerConfig) (*ESBulkIndexer, error) {
+	retryBackoff := backoff.NewExponentialBackOff()
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{
+		RetryOnStatus: []int{502, 503, 504, 429},
+		RetryBackoff: func(i int) time.Duration {
+			if i == 1 {
+				retryBackoff.Reset()
+			}
+			return retryBackoff.NextBackOff()
+		},
+		MaxRetries: cfg.MaxRetries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bi, err := esutil.NewBulkIndexer(esutil.BulkIndexerConfig{
+		Index:         cfg.Index,
+		Client:        es,
+		NumWorkers:    cfg.NumWorkers,
+		FlushBytes:    cfg.FlushBytes,
+		FlushInterval: 30 * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ESBulkIndexer{bi: bi}, nil
+}
+
+func (s *ESBulkIndexer) Add(ctx context.Context, id, source string, body []byte) error {
+	return s.bi.Add(ctx, esutil.BulkIndexerItem{
+		Action:     "index",
+		DocumentID: id,
+		Body:       bytes.NewReader(body),
+		OnSuccess: func(context.Context, esutil.BulkIndexerItem, esutil.BulkIndexerResponseItem) {
+			atomic.AddUint64(&s.succeeded, 1)
+		},
+		OnFailure: func(_ context.Context, item esutil.BulkIndexerItem, res esutil.BulkIndexerResponseItem, err error) {
+			atomic.AddUint64(&s.failed, 1)
+
+			if err != nil {
+				log.Printf("ERROR: [%s] %s %s", item.DocumentID, err, source)
+				fmt.Printf(`{"err":"%s","itemId":"%s","doc":%s},`+"\n", err, item.DocumentID, body)
+			} else {
+				log.Printf("ERROR: [%s] %s: %s %s", item.DocumentID, res.Error.Type, res.Error.Reason, source)
+				fmt.Printf(`{"err":"%s","reason":"%s","itemId":"%s","doc":%s},`+"\n", res.Error.Type, res.Error.Reason, item.DocumentID, body)
+			}
+		},
+	})
+}
+
+func (s *ESBulkIndexer) Close(ctx context.Context) error {
+	return s.bi.Close(ctx)
+}
+
+func (s *ESBulkIndexer) Stats() SinkStats {
+	biStats := s.bi.Stats()
+	return SinkStats{
+		NumAdded:   atomic.LoadUint64(&s.succeeded) + atomic.LoadUint64(&s.failed),
+		NumFlushed: biStats.NumFlushed,
+		NumFailed:  biStats.NumFailed,
+	}
+}
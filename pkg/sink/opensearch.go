@@ -0,0 +1,82 @@
+//go:build opensearch
+
+package sink
+
+import (
+	"context"
+	"sync/atomic"
+
+	elastic "github.com/olivere/elastic/v7"
+)
+
+// OpenSearchConfig configures NewOpenSearchSink.
+type OpenSearchConfig struct {
+	URL        string
+	Index      string
+	NumWorkers int
+	FlushBytes int
+}
+
+// OpenSearchSink is a Sink backed by olivere/elastic's bulk processor,
+// for clusters (OpenSearch, older Elasticsearch) that don't speak the
+// go-elasticsearch/v8 client's wire protocol. Built only when the
+// "opensearch" build tag is set, so the default build doesn't pull in a
+// second Elasticsearch client library.
+type OpenSearchSink struct {
+	bp     *elastic.BulkProcessor
+	index  string
+	added  uint64
+	failed uint64
+}
+
+// NewOpenSearchSink dials cfg.URL and starts a bulk processor targeting
+// cfg.Index.
+func NewOpenSearchSink(cfg OpenSearchConfig) (*OpenSearchSink, error) {
+	client, err := elastic.NewClient(elastic.SetURL(cfg.URL))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &OpenSearchSink{index: cfg.Index}
+
+	bp, err := client.BulkProcessor().
+		Workers(cfg.NumWorkers).
+		BulkSize(cfg.FlushBytes).
+		After(func(_ int64, requests []elastic.BulkableRequest, resp *elastic.BulkResponse, err error) {
+			if err != nil {
+				// The bulk request itself failed (e.g. connection error);
+				// resp is nil in this case, so there's nothing to inspect
+				// per-item and every request in the batch counts as failed.
+				atomic.AddUint64(&s.failed, uint64(len(requests)))
+				return
+			}
+			atomic.AddUint64(&s.failed, uint64(len(resp.Failed())))
+		}).
+		Do(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	s.bp = bp
+	return s, nil
+}
+
+func (s *OpenSearchSink) Add(_ context.Context, id, _ string, body []byte) error {
+	req := elastic.NewBulkIndexRequest().Index(s.index).Id(id).Doc(string(body))
+	s.bp.Add(req)
+	atomic.AddUint64(&s.added, 1)
+	return nil
+}
+
+func (s *OpenSearchSink) Close(context.Context) error {
+	return s.bp.Close()
+}
+
+func (s *OpenSearchSink) Stats() SinkStats {
+	stats := s.bp.Stats()
+	return SinkStats{
+		NumAdded:   atomic.LoadUint64(&s.added),
+		NumFlushed: uint64(stats.Succeeded),
+		NumFailed:  atomic.LoadUint64(&s.failed),
+	}
+}
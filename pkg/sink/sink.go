@@ -0,0 +1,29 @@
+// Package sink abstracts the destination that parsed ZDARZENIE documents
+// are written to, so the indexer can target Elasticsearch, a plain file,
+// or anything else implementing Sink, without changing its main loop.
+package sink
+
+import "context"
+
+// SinkStats summarises what a Sink has done so far. Not every field is
+// meaningful for every implementation (e.g. NDJSONFileSink never fails a
+// document), but all of them are safe to read at any time.
+type SinkStats struct {
+	NumAdded   uint64
+	NumFlushed uint64
+	NumFailed  uint64
+}
+
+// Sink accepts documents produced by sewik.Docs and delivers them
+// somewhere: Elasticsearch, a file on disk, another search backend, ...
+type Sink interface {
+	// Add queues or writes one document. id may be empty, in which case
+	// the backend is expected to generate one. source is the originating
+	// filename, kept for diagnostics. body is the raw JSON document.
+	Add(ctx context.Context, id, source string, body []byte) error
+	// Close flushes any buffered documents and releases resources. No
+	// further calls to Add are valid afterwards.
+	Close(ctx context.Context) error
+	// Stats reports progress so far. Safe to call before Close.
+	Stats() SinkStats
+}
@@ -0,0 +1,53 @@
+package parse
+
+import "fmt"
+
+// ParseOptions bounds the resources a single parse is allowed to consume,
+// so that a hostile or merely huge ZDARZENIE file cannot exhaust the
+// process's stack or memory.
+type ParseOptions struct {
+	// MaxDepth caps how many elements may be nested inside one another.
+	MaxDepth int
+	// MaxElements caps the total number of elements in a document.
+	MaxElements int
+	// MaxAttributes caps the number of attributes on any single element.
+	MaxAttributes int
+	// MaxTextLength caps the length, in bytes, of any single text node.
+	MaxTextLength int
+}
+
+// DefaultParseOptions returns the limits applied when no ParseOptions are
+// given explicitly. They are generous enough for any legitimate ZDARZENIE
+// export seen in practice, while still bounding worst-case stack and
+// memory use on untrusted input.
+func DefaultParseOptions() ParseOptions {
+	return ParseOptions{
+		MaxDepth:      256,
+		MaxElements:   1 << 20,
+		MaxAttributes: 256,
+		MaxTextLength: 1 << 20,
+	}
+}
+
+// LimitKind identifies which ParseOptions limit a ParseError refers to.
+type LimitKind string
+
+const (
+	LimitDepth      LimitKind = "depth"
+	LimitElements   LimitKind = "elements"
+	LimitAttributes LimitKind = "attributes"
+	LimitTextLength LimitKind = "text_length"
+)
+
+// ParseError reports that a document was rejected because it exceeded one
+// of the configured ParseOptions limits.
+type ParseError struct {
+	Kind    LimitKind
+	Element string
+	Limit   int
+	Value   int
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse: %s exceeds limit at <%s>: %d > %d", e.Kind, e.Element, e.Value, e.Limit)
+}
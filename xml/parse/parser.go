@@ -11,17 +11,26 @@ import (
 	"golang.org/x/net/html/charset"
 )
 
-func File(filename string) (*xmldom.Document, error) {
+// File parses filename into a full xmldom.Document. If opts is omitted,
+// DefaultParseOptions is used.
+func File(filename string, opts ...ParseOptions) (*xmldom.Document, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	return parse(file)
+	return parse(file, resolveOptions(opts))
 }
 
-func parse(r io.Reader) (*xmldom.Document, error) {
+func resolveOptions(opts []ParseOptions) ParseOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return DefaultParseOptions()
+}
+
+func parse(r io.Reader, opts ParseOptions) (*xmldom.Document, error) {
 	p := xml.NewDecoder(r)
 	p.CharsetReader = charset.NewReaderLabel
 
@@ -32,9 +41,25 @@ func parse(r io.Reader) (*xmldom.Document, error) {
 
 	doc := new(xmldom.Document)
 	var e *xmldom.Node
+	depth := 0
+	elements := 0
 	for t != nil {
 		switch token := t.(type) {
 		case xml.StartElement:
+			depth++
+			if depth > opts.MaxDepth {
+				return nil, &ParseError{Kind: LimitDepth, Element: token.Name.Local, Limit: opts.MaxDepth, Value: depth}
+			}
+
+			elements++
+			if elements > opts.MaxElements {
+				return nil, &ParseError{Kind: LimitElements, Element: token.Name.Local, Limit: opts.MaxElements, Value: elements}
+			}
+
+			if len(token.Attr) > opts.MaxAttributes {
+				return nil, &ParseError{Kind: LimitAttributes, Element: token.Name.Local, Limit: opts.MaxAttributes, Value: len(token.Attr)}
+			}
+
 			// a new node
 			el := new(xmldom.Node)
 			el.Document = doc
@@ -55,9 +80,17 @@ func parse(r io.Reader) (*xmldom.Document, error) {
 				doc.Root = e
 			}
 		case xml.EndElement:
+			depth--
 			e = e.Parent
 		case xml.CharData:
 			// text node
+			if len(token) > opts.MaxTextLength {
+				name := ""
+				if e != nil {
+					name = e.Name
+				}
+				return nil, &ParseError{Kind: LimitTextLength, Element: name, Limit: opts.MaxTextLength, Value: len(token)}
+			}
 			if e != nil {
 				e.Text = string(bytes.TrimSpace(token))
 			}
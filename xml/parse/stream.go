@@ -0,0 +1,141 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+
+	"github.com/subchen/go-xmldom"
+	"golang.org/x/net/html/charset"
+)
+
+// StartEvent is handed to a Stream handler once a target element and all
+// of its descendants have been read from the decoder.
+type StartEvent struct {
+	// Name is the local name of the matched element, i.e. the one that
+	// appeared in targets.
+	Name string
+	// Node is the buffered subtree rooted at the matched element. It has
+	// the same shape as the nodes produced by File/parse, but none of its
+	// siblings or ancestors are populated.
+	Node *xmldom.Node
+}
+
+// Stream decodes r one token at a time and only buffers the subtrees
+// whose root element name appears in targets, handing each one to handler
+// as it completes. Tokens outside of a target subtree are read and
+// discarded one at a time rather than being built into a tree, so a
+// caller can process a file with an unbounded number of ZDARZENIE
+// elements, at any nesting depth, in constant per-worker memory. Callers
+// that need the whole document should use File instead.
+func Stream(r io.Reader, targets []string, handler func(StartEvent) error) error {
+	return StreamWithOptions(r, targets, handler, DefaultParseOptions())
+}
+
+// StreamWithOptions is Stream with explicit ParseOptions applied to each
+// buffered subtree.
+func StreamWithOptions(r io.Reader, targets []string, handler func(StartEvent) error, opts ParseOptions) error {
+	wanted := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		wanted[t] = true
+	}
+
+	d := xml.NewDecoder(r)
+	d.CharsetReader = charset.NewReaderLabel
+
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if !wanted[start.Name.Local] {
+			// Not a target: don't Skip, since that would discard its
+			// whole subtree and any targets nested inside it. Just
+			// leave it for the Token loop to walk into normally.
+			continue
+		}
+
+		node, err := buildSubtree(d, start, opts)
+		if err != nil {
+			return err
+		}
+
+		if err := handler(StartEvent{Name: start.Name.Local, Node: node}); err != nil {
+			return err
+		}
+	}
+}
+
+// buildSubtree reads tokens iteratively, using an explicit stack rather
+// than recursion, until the subtree rooted at start is fully read, and
+// returns it as an xmldom.Node. opts bounds its depth, element count,
+// attribute count and text length the same way parse does for whole
+// documents.
+func buildSubtree(d *xml.Decoder, start xml.StartElement, opts ParseOptions) (*xmldom.Node, error) {
+	if len(start.Attr) > opts.MaxAttributes {
+		return nil, &ParseError{Kind: LimitAttributes, Element: start.Name.Local, Limit: opts.MaxAttributes, Value: len(start.Attr)}
+	}
+
+	root := nodeFromStart(start)
+	stack := []*xmldom.Node{root}
+	elements := 1
+
+	for len(stack) > 0 {
+		if len(stack) > opts.MaxDepth {
+			return nil, &ParseError{Kind: LimitDepth, Element: stack[len(stack)-1].Name, Limit: opts.MaxDepth, Value: len(stack)}
+		}
+
+		t, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		cur := stack[len(stack)-1]
+
+		switch token := t.(type) {
+		case xml.StartElement:
+			elements++
+			if elements > opts.MaxElements {
+				return nil, &ParseError{Kind: LimitElements, Element: token.Name.Local, Limit: opts.MaxElements, Value: elements}
+			}
+			if len(token.Attr) > opts.MaxAttributes {
+				return nil, &ParseError{Kind: LimitAttributes, Element: token.Name.Local, Limit: opts.MaxAttributes, Value: len(token.Attr)}
+			}
+
+			child := nodeFromStart(token)
+			child.Parent = cur
+			cur.Children = append(cur.Children, child)
+			stack = append(stack, child)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(token) > opts.MaxTextLength {
+				return nil, &ParseError{Kind: LimitTextLength, Element: cur.Name, Limit: opts.MaxTextLength, Value: len(token)}
+			}
+			cur.Text = string(bytes.TrimSpace(token))
+		}
+	}
+
+	return root, nil
+}
+
+func nodeFromStart(start xml.StartElement) *xmldom.Node {
+	el := new(xmldom.Node)
+	el.Name = start.Name.Local
+	for _, attr := range start.Attr {
+		el.Attributes = append(el.Attributes, &xmldom.Attribute{
+			Name:  attr.Name.Local,
+			Value: attr.Value,
+		})
+	}
+	return el
+}
@@ -0,0 +1,29 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamFindsTargetsNestedUnderNonMatchingRoot(t *testing.T) {
+	xml := `<eksport><zdarzenie id="1"/><zdarzenie id="2"/></eksport>`
+
+	var got []string
+	err := Stream(strings.NewReader(xml), []string{"zdarzenie"}, func(ev StartEvent) error {
+		got = append(got, ev.Node.GetAttributeValue("id"))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream: %s", err)
+	}
+
+	want := []string{"1", "2"}
+	if len(got) != len(want) {
+		t.Fatalf("handler fired %d times, want %d (got %v)", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
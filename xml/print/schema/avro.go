@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"strings"
+
+	"sewik/pkg/dom/stats"
+)
+
+// Avro builds an Apache Avro record schema for elems, using the same
+// counts as JSON: a required field for a child seen on every occurrence
+// of its parent, an "array" wrapper for one seen more often than its
+// parent, and a nullable ["null", <record>] union for an optional one.
+//
+// Every record is namespaced by the path of element names above it, so
+// that an element name recurring at more than one nesting depth (e.g.
+// ADRES under both OSOBA and ZDARZENIE) produces distinct Avro fullnames
+// instead of two conflicting definitions of the same name.
+func Avro(name string, elems stats.Elements) map[string]interface{} {
+	if elems.Len() == 1 {
+		for elName, el := range elems.Get() {
+			return avroRecord(elName, nil, el.Cn, el.At, el.El, 0)
+		}
+	}
+
+	return avroRecordFields(name, nil, elems, 0)
+}
+
+// PrintAvro writes the Avro record schema for elems, named name, to
+// stdout.
+func PrintAvro(name string, elems stats.Elements) {
+	printJSON(Avro(name, elems))
+}
+
+func avroRecordFields(name string, path []string, elems stats.Elements, parentCn int) map[string]interface{} {
+	fields := make([]map[string]interface{}, 0, elems.Len())
+	for childName, child := range elems.Get() {
+		fields = append(fields, avroField(childName, path, child.Cn, child.At, child.El, parentCn))
+	}
+
+	return map[string]interface{}{
+		"type":   "record",
+		"name":   name,
+		"fields": fields,
+	}
+}
+
+func avroRecord(name string, path []string, cn int, at stats.Attributes, children stats.Elements, parentCn int) map[string]interface{} {
+	fields := make([]map[string]interface{}, 0, at.Len()+children.Len())
+
+	for attrName := range at.Get() {
+		fields = append(fields, map[string]interface{}{
+			"name": attrName,
+			"type": "string",
+		})
+	}
+
+	childPath := append(append([]string{}, path...), name)
+	for childName, child := range children.Get() {
+		fields = append(fields, avroField(childName, childPath, child.Cn, child.At, child.El, cn))
+	}
+
+	record := map[string]interface{}{
+		"type":   "record",
+		"name":   name,
+		"fields": fields,
+	}
+	if len(path) > 0 {
+		record["namespace"] = strings.Join(path, ".")
+	}
+	return record
+}
+
+func avroField(name string, path []string, cn int, at stats.Attributes, children stats.Elements, parentCn int) map[string]interface{} {
+	record := avroRecord(name, path, cn, at, children, parentCn)
+
+	var fieldType interface{} = record
+	if parentCn > 0 && cn > parentCn {
+		fieldType = map[string]interface{}{"type": "array", "items": record}
+	} else if parentCn > 0 && cn < parentCn {
+		fieldType = []interface{}{"null", record}
+	}
+
+	return map[string]interface{}{
+		"name": name,
+		"type": fieldType,
+	}
+}
@@ -0,0 +1,97 @@
+// Package schema turns the element structure discovered by a scan
+// (counts, optionality, attribute value distributions, held in the same
+// stats.Elements tree the "x" command prints) into schemas that
+// downstream systems can validate or ingest against, instead of the
+// bespoke pseudo-XML dump that stats.PrintXML writes.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"sewik/pkg/dom/stats"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSON builds a Draft 2020-12 JSON Schema describing elems: each element
+// becomes an object, its children become properties, its attributes
+// become string properties, a child whose count is lower than its
+// parent's is optional, and a child seen more often than its parent
+// repeats and so becomes an array.
+func JSON(elems stats.Elements) map[string]interface{} {
+	s := elementsSchema(elems, 0)
+	s["$schema"] = jsonSchemaDraft
+	return s
+}
+
+// PrintJSON writes the JSON Schema for elems to stdout.
+func PrintJSON(elems stats.Elements) {
+	printJSON(JSON(elems))
+}
+
+func elementsSchema(elems stats.Elements, parentCn int) map[string]interface{} {
+	if elems.Len() == 1 {
+		for _, el := range elems.Get() {
+			return elementSchema(el.Cn, el.At, el.El, parentCn)
+		}
+	}
+
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for name, el := range elems.Get() {
+		properties[name] = elementSchema(el.Cn, el.At, el.El, parentCn)
+		if parentCn == 0 || el.Cn >= parentCn {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func elementSchema(cn int, at stats.Attributes, children stats.Elements, parentCn int) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for name := range at.Get() {
+		properties[name] = map[string]interface{}{"type": "string"}
+		required = append(required, name)
+	}
+
+	for name, child := range children.Get() {
+		properties[name] = elementSchema(child.Cn, child.At, child.El, cn)
+		if child.Cn >= cn {
+			required = append(required, name)
+		}
+	}
+
+	object := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+
+	if parentCn > 0 && cn > parentCn {
+		return map[string]interface{}{
+			"type":  "array",
+			"items": object,
+		}
+	}
+
+	return object
+}
+
+func printJSON(v interface{}) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "schema: %s\n", err)
+		return
+	}
+	fmt.Println(string(b))
+}
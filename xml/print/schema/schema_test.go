@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"sewik/pkg/dom"
+	"sewik/pkg/dom/stats"
+)
+
+func TestJSONRequiredIsNeverNull(t *testing.T) {
+	elements := stats.NewElementsWithLock()
+	elements.Add(&dom.Element{
+		Name: "ZDARZENIE",
+		Children: []*dom.Element{
+			{Name: "LEAF"},
+		},
+	})
+
+	b, err := json.Marshal(JSON(elements))
+	if err != nil {
+		t.Fatalf("json.Marshal: %s", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal: %s", err)
+	}
+
+	assertRequiredIsArray(t, decoded)
+}
+
+// assertRequiredIsArray walks a decoded schema node and every nested
+// "properties" entry, failing if any "required" key decoded as nil
+// (i.e. was emitted as JSON null instead of an array).
+func assertRequiredIsArray(t *testing.T, node map[string]interface{}) {
+	t.Helper()
+
+	if required, ok := node["required"]; ok {
+		if _, isSlice := required.([]interface{}); !isSlice {
+			t.Fatalf(`"required" = %#v (%T), want a []interface{}`, required, required)
+		}
+	}
+
+	if properties, ok := node["properties"].(map[string]interface{}); ok {
+		for _, child := range properties {
+			if childObj, ok := child.(map[string]interface{}); ok {
+				assertRequiredIsArray(t, childObj)
+			}
+		}
+	}
+}